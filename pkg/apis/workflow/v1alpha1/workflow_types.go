@@ -0,0 +1,186 @@
+package v1alpha1
+
+// Template is a reusable and composable unit of execution in a workflow.
+// NOTE: this is a partial definition containing only the fields needed by the
+// resource executor. The full Template type also carries Container, Script,
+// Steps, DAG, etc.
+type Template struct {
+	// Name is the name of the template
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+
+	// Resource template subtype which can run k8s resources
+	Resource *ResourceTemplate `json:"resource,omitempty" protobuf:"bytes,2,opt,name=resource"`
+
+	// ActiveDeadlineSeconds is the duration in seconds the template is allowed to run
+	// before the step is terminated, including any WaitFor polling on a resource step.
+	ActiveDeadlineSeconds int64 `json:"activeDeadlineSeconds,omitempty" protobuf:"varint,3,opt,name=activeDeadlineSeconds"`
+}
+
+// ResourceTemplate is a template subtype to manipulate kubernetes resources
+type ResourceTemplate struct {
+	// Action is the action to perform to the resource.
+	// Must be one of: get, create, apply, delete, replace, patch, preview, assert,
+	// server-side-apply
+	// preview is a server-side dry-run of create/apply: it never mutates the
+	// cluster and reports the change it would have made as a node output.
+	// assert fetches the object and evaluates Assertions against it without
+	// creating, patching or deleting anything.
+	// server-side-apply uses `kubectl apply --server-side` with FieldManager so the
+	// workflow can coexist with GitOps controllers that own parts of the same object.
+	Action string `json:"action" protobuf:"bytes,1,opt,name=action"`
+
+	// MergeStrategy is the strategy used to merge a patch. It defaults to "strategic"
+	// Must be one of: strategic, merge, json
+	MergeStrategy string `json:"mergeStrategy,omitempty" protobuf:"bytes,2,opt,name=mergeStrategy"`
+
+	// Manifest contains the kubernetes manifest
+	Manifest string `json:"manifest,omitempty" protobuf:"bytes,3,opt,name=manifest"`
+
+	// SetOwnerReference sets the reference to the workflow on the OwnerReference of generated resource.
+	SetOwnerReference bool `json:"setOwnerReference,omitempty" protobuf:"varint,4,opt,name=setOwnerReference"`
+
+	// SuccessCondition is a label selector expression which is used to determine whether a resource is considered successful
+	SuccessCondition string `json:"successCondition,omitempty" protobuf:"bytes,5,opt,name=successCondition"`
+
+	// FailureCondition is a label selector expression which is used to determine whether a resource is considered failed
+	FailureCondition string `json:"failureCondition,omitempty" protobuf:"bytes,6,opt,name=failureCondition"`
+
+	// Flags is a set of additional options passed to kubectl before submitting a resource
+	// I.e. to disable resource validation:
+	// flags: [
+	// 	"--validate=false"  # disable resource validation
+	// ]
+	Flags []string `json:"flags,omitempty" protobuf:"bytes,7,rep,name=flags"`
+
+	// Assertions is the list of expectations evaluated against the fetched object when
+	// Action is "assert". All assertions are evaluated and every failure is reported,
+	// rather than stopping at the first one.
+	Assertions []ResourceAssertion `json:"assertions,omitempty" protobuf:"bytes,8,rep,name=assertions"`
+
+	// KubeConfig, when set, targets this resource step at an external cluster instead
+	// of the pod's in-cluster API server.
+	KubeConfig *ResourceKubeConfig `json:"kubeConfig,omitempty" protobuf:"bytes,9,opt,name=kubeConfig"`
+
+	// WaitFor is a kubectl-wait-style predicate evaluated after create/apply/replace/
+	// server-side-apply, e.g. "condition=Available", "condition=Complete", "delete" or
+	// "jsonpath={.status.readyReplicas}=3". For well-known kinds a kind-aware readiness
+	// check is used instead of a raw status.conditions lookup, for these predicates only:
+	// condition=Available on Deployment, StatefulSet and DaemonSet, condition=Complete on
+	// Job, condition=Ready on Pod and condition=Bound on PersistentVolumeClaim. Any other
+	// "condition=" predicate, and every other kind including CRDs, falls back to the
+	// standard status.conditions[].type/status check.
+	WaitFor string `json:"waitFor,omitempty" protobuf:"bytes,10,opt,name=waitFor"`
+
+	// FieldManager is the field manager used with Action "server-side-apply". Optional:
+	// when empty, no --field-manager flag is passed and kubectl's own default applies.
+	FieldManager string `json:"fieldManager,omitempty" protobuf:"bytes,11,opt,name=fieldManager"`
+
+	// ForceConflicts, when true, takes ownership of fields in conflict with Action
+	// "server-side-apply". Without it, a conflicting apply fails and the conflicting
+	// field paths and owning managers are surfaced as a node output.
+	ForceConflicts bool `json:"forceConflicts,omitempty" protobuf:"varint,12,opt,name=forceConflicts"`
+
+	// Subresource restricts a "server-side-apply" Action to a particular subresource,
+	// e.g. "status".
+	Subresource string `json:"subresource,omitempty" protobuf:"bytes,13,opt,name=subresource"`
+
+	// LintPolicy configures the pre-flight checks run over the manifest before
+	// create/apply/patch/server-side-apply. Checks left unset are not run.
+	LintPolicy *LintPolicy `json:"lintPolicy,omitempty" protobuf:"bytes,14,opt,name=lintPolicy"`
+}
+
+// LintSeverity controls how a single pre-flight lint check's result is treated.
+type LintSeverity string
+
+const (
+	// LintSeverityWarn logs the finding but does not fail the step. Lint findings are
+	// not exposed as node outputs.
+	LintSeverityWarn LintSeverity = "warn"
+	// LintSeverityError fails the step before any cluster mutation happens
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityDisabled skips the check entirely
+	LintSeverityDisabled LintSeverity = "disabled"
+)
+
+// LintPolicy configures the built-in pre-flight checks run over a resource's manifest
+// before it is created, applied or patched, inspired by common cluster-linting tools. A
+// check is only run when given a severity; the zero value for each field is equivalent
+// to LintSeverityDisabled.
+type LintPolicy struct {
+	// MissingResourceLimits flags containers with no resources.requests/limits set
+	MissingResourceLimits LintSeverity `json:"missingResourceLimits,omitempty" protobuf:"bytes,1,opt,name=missingResourceLimits,casttype=LintSeverity"`
+
+	// LatestImageTag flags containers using the "latest" image tag, or no tag at all
+	LatestImageTag LintSeverity `json:"latestImageTag,omitempty" protobuf:"bytes,2,opt,name=latestImageTag,casttype=LintSeverity"`
+
+	// PrivilegedOrHostNamespace flags privileged containers, or pods using hostNetwork/hostPID
+	PrivilegedOrHostNamespace LintSeverity `json:"privilegedOrHostNamespace,omitempty" protobuf:"bytes,3,opt,name=privilegedOrHostNamespace,casttype=LintSeverity"`
+
+	// MissingProbes flags containers with no readiness or liveness probe configured
+	MissingProbes LintSeverity `json:"missingProbes,omitempty" protobuf:"bytes,4,opt,name=missingProbes,casttype=LintSeverity"`
+
+	// DeprecatedAPIVersion flags a manifest whose apiVersion/kind the target server's
+	// discovery API no longer serves
+	DeprecatedAPIVersion LintSeverity `json:"deprecatedApiVersion,omitempty" protobuf:"bytes,5,opt,name=deprecatedApiVersion,casttype=LintSeverity"`
+
+	// NamespaceMismatch flags a manifest whose metadata.namespace doesn't match the
+	// executor's namespace
+	NamespaceMismatch LintSeverity `json:"namespaceMismatch,omitempty" protobuf:"bytes,6,opt,name=namespaceMismatch,casttype=LintSeverity"`
+}
+
+// ResourceKubeConfig points to a kubeconfig used to target a resource step at a cluster
+// other than the one the workflow pod is running in.
+type ResourceKubeConfig struct {
+	// SecretRef is a reference to the secret key holding the kubeconfig contents. Either
+	// SecretRef or VolumePath must be set.
+	SecretRef *SecretKeySelector `json:"secretRef,omitempty" protobuf:"bytes,1,opt,name=secretRef"`
+
+	// VolumePath is a path to a kubeconfig file that has already been projected into the
+	// wait container, as an alternative to SecretRef.
+	VolumePath string `json:"volumePath,omitempty" protobuf:"bytes,2,opt,name=volumePath"`
+
+	// Context is the kubeconfig context to use. Defaults to the kubeconfig's current-context.
+	Context string `json:"context,omitempty" protobuf:"bytes,3,opt,name=context"`
+}
+
+// SecretKeySelector selects a key of a secret
+type SecretKeySelector struct {
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	Key  string `json:"key" protobuf:"bytes,2,opt,name=key"`
+}
+
+// ResourceAssertion is a single expectation checked against the live object fetched by
+// an `assert` resource action. Exactly one of the fields below should be set.
+type ResourceAssertion struct {
+	// Exists asserts that the resource exists when true, or that it does not when false
+	Exists *bool `json:"exists,omitempty" protobuf:"varint,1,opt,name=exists"`
+
+	// FieldEquals asserts that the JSONPath at Path equals Value
+	FieldEquals *FieldEqualsAssertion `json:"fieldEquals,omitempty" protobuf:"bytes,2,opt,name=fieldEquals"`
+
+	// FieldMatches asserts that the JSONPath at Path matches the Regex
+	FieldMatches *FieldMatchesAssertion `json:"fieldMatches,omitempty" protobuf:"bytes,3,opt,name=fieldMatches"`
+
+	// Count asserts the number of elements found at Path against Value using Op
+	Count *CountAssertion `json:"count,omitempty" protobuf:"bytes,4,opt,name=count"`
+}
+
+// FieldEqualsAssertion asserts that a JSONPath resolves to an exact string value
+type FieldEqualsAssertion struct {
+	Path  string `json:"path" protobuf:"bytes,1,opt,name=path"`
+	Value string `json:"value" protobuf:"bytes,2,opt,name=value"`
+}
+
+// FieldMatchesAssertion asserts that a JSONPath resolves to a value matching a regex
+type FieldMatchesAssertion struct {
+	Path  string `json:"path" protobuf:"bytes,1,opt,name=path"`
+	Regex string `json:"regex" protobuf:"bytes,2,opt,name=regex"`
+}
+
+// CountAssertion asserts the number of elements found at a JSONPath using a comparison
+// operator. Op must be one of: ==, !=, <, <=, >, >=. Op defaults to "==".
+type CountAssertion struct {
+	Path  string `json:"path" protobuf:"bytes,1,opt,name=path"`
+	Op    string `json:"op,omitempty" protobuf:"bytes,2,opt,name=op"`
+	Value int    `json:"value" protobuf:"varint,3,opt,name=value"`
+}
@@ -0,0 +1,12 @@
+package common
+
+import "time"
+
+// ExecutionControl contains execution control parameters for the executor sidecar
+type ExecutionControl struct {
+	// Deadline is a max timestamp in which a container must have completed execution by
+	Deadline *time.Time `json:"deadline,omitempty"`
+
+	// IncludeScriptOutput is a flag to inform the executor to include the log for script outputs
+	IncludeScriptOutput bool `json:"includeScriptOutput,omitempty"`
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/common"
+)
+
+// WorkflowExecutor is a container for the workflow executor sidecar. It carries the
+// template of the step it is executing along with the various clients needed to
+// carry out the template's action.
+type WorkflowExecutor struct {
+	PodName  string
+	Template wfv1.Template
+
+	// ClientSet is a client for the in-cluster Kubernetes API server
+	ClientSet kubernetes.Interface
+
+	// Namespace of the workflow
+	Namespace string
+
+	// PodAnnotationsPath is the path to the file containing the annotations of the pod
+	PodAnnotationsPath string
+
+	// ExecutionControl is the execution control parameters set by the controller, e.g. timeouts
+	ExecutionControl *common.ExecutionControl
+
+	// RuntimeExecutor is the runtime specific executor, e.g. docker, kubelet, pns
+	RuntimeExecutor ContainerRuntimeExecutor
+}
+
+// ContainerRuntimeExecutor is the interface for interacting with a container runtime, for things
+// such as monitoring logs and copying artifacts in and out of a running container.
+type ContainerRuntimeExecutor interface {
+	// GetFileContents returns the file contents of a file in the main container
+	GetFileContents(containerName string, sourcePath string) (string, error)
+
+	// CopyFile copies a source file in a container to a local path
+	CopyFile(containerName string, sourcePath string, destPath string, compressionLevel int) error
+
+	// GetOutputStream returns the entirety of the container output as a io.Reader
+	GetOutputStream(ctx context.Context, containerName string, combinedOutput bool) (io.ReadCloser, error)
+
+	// Wait waits for the container to complete
+	Wait(ctx context.Context, containerNames []string) error
+
+	// Kill a list of containers first with a SIGTERM then with a SIGKILL after a grace period
+	Kill(ctx context.Context, containerNames []string, terminationGracePeriodDuration time.Duration) error
+}
@@ -0,0 +1,7 @@
+package executor
+
+const (
+	fakePodName     = "fake-test-pod"
+	fakeNamespace   = "fake-test-namespace"
+	fakeAnnotations = "/tmp/podannotationspath"
+)
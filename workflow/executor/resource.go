@@ -0,0 +1,287 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ExecResource will run kubectl action against a manifest
+func (we *WorkflowExecutor) ExecResource(ctx context.Context, action string, manifestPath string, flags []string) (string, string, error) {
+	kubeConfig, cleanup, err := we.materializeKubeConfig(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	switch action {
+	case "create", "apply", "patch", "server-side-apply":
+		if _, err := we.lintManifest(ctx, manifestPath, kubeConfig); err != nil {
+			return "", "", err
+		}
+	}
+
+	if action == "preview" {
+		return we.previewResource(ctx, manifestPath, flags, kubeConfig)
+	}
+	if action == "assert" {
+		return we.assertResource(ctx, manifestPath, kubeConfig)
+	}
+	if action == "server-side-apply" {
+		return we.serverSideApplyResource(ctx, manifestPath, flags, kubeConfig)
+	}
+
+	args, err := we.getKubectlArguments(action, manifestPath, flags)
+	if err != nil {
+		return "", "", err
+	}
+	args = append(args, kubeConfig.kubectlFlags()...)
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+
+	switch action {
+	case "create", "apply", "replace", "patch":
+		// SuccessCondition/FailureCondition are evaluated after create/apply/replace/patch
+		// (not after delete), independent of WaitFor (which only fires when explicitly set on the template).
+		if err := we.checkConditions(ctx, manifestPath, kubeConfig); err != nil {
+			return string(out), "", err
+		}
+	}
+
+	switch action {
+	case "create", "apply", "replace":
+		if err := we.waitForResource(ctx, manifestPath, kubeConfig); err != nil {
+			return string(out), "", err
+		}
+	}
+
+	return string(out), "", nil
+}
+
+// previewResource performs a server-side dry-run of the manifest (falling back to a
+// client-side dry-run when the server rejects it, e.g. an older API server or an
+// admission webhook that doesn't support dryRun) and reports the change it would have
+// made without ever mutating the cluster. The structured diff is returned as the node's
+// output so downstream steps can gate on drift before a real apply step runs.
+func (we *WorkflowExecutor) previewResource(ctx context.Context, manifestPath string, flags []string, kubeConfig *materializedKubeConfig) (string, string, error) {
+	args, err := we.getKubectlArguments("preview", manifestPath, flags)
+	if err != nil {
+		return "", "", err
+	}
+	args = append(args, kubeConfig.kubectlFlags()...)
+
+	proposedJSON, err := exec.CommandContext(ctx, "kubectl", args...).Output()
+	if err != nil {
+		// server-side dry-run may be rejected (e.g. API server < 1.13, or a webhook
+		// that doesn't implement dryRun support) -- fall back to a client-side dry-run.
+		clientArgs := make([]string, len(args))
+		copy(clientArgs, args)
+		for i, a := range clientArgs {
+			if a == "--dry-run=server" {
+				clientArgs[i] = "--dry-run=client"
+			}
+		}
+		proposedJSON, err = exec.CommandContext(ctx, "kubectl", clientArgs...).Output()
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	getArgs := append([]string{"get", "-f", manifestPath, "-o", "json"}, kubeConfig.kubectlFlags()...)
+	liveJSON, getErr := exec.CommandContext(ctx, "kubectl", getArgs...).Output()
+	if getErr != nil {
+		// object doesn't exist yet: that's a legitimate "create" preview, not an error
+		liveJSON = nil
+	}
+
+	diff, err := computeResourceDiff(liveJSON, proposedJSON)
+	if err != nil {
+		return "", "", err
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return "", "", err
+	}
+
+	successReqs, failReqs, err := we.conditionRequirements()
+	if err != nil {
+		return "", "", err
+	}
+	if len(successReqs) > 0 || len(failReqs) > 0 {
+		if _, err := matchConditions(proposedJSON, successReqs, failReqs); err != nil {
+			return string(diffJSON), "", err
+		}
+	}
+
+	return string(diffJSON), "", nil
+}
+
+// conditionRequirements parses the resource template's success/failure conditions into
+// label-selector-style requirements that can be evaluated with matchConditions.
+func (we *WorkflowExecutor) conditionRequirements() (labels.Requirements, labels.Requirements, error) {
+	if we.Template.Resource == nil {
+		return nil, nil, nil
+	}
+	var successReqs labels.Requirements
+	if sc := we.Template.Resource.SuccessCondition; sc != "" {
+		selector, err := labels.Parse(sc)
+		if err != nil {
+			return nil, nil, err
+		}
+		successReqs, _ = selector.Requirements()
+	}
+	var failReqs labels.Requirements
+	if fc := we.Template.Resource.FailureCondition; fc != "" {
+		selector, err := labels.Parse(fc)
+		if err != nil {
+			return nil, nil, err
+		}
+		failReqs, _ = selector.Requirements()
+	}
+	return successReqs, failReqs, nil
+}
+
+// getKubectlArguments returns the arguments to pass to `kubectl` in order to carry out
+// the resource template's action against the given manifest.
+func (we *WorkflowExecutor) getKubectlArguments(action string, manifestPath string, flags []string) ([]string, error) {
+	kubectlVerb := action
+	switch action {
+	case "preview":
+		// preview is a client-side concept: kubectl itself only knows "apply" plus a
+		// dry-run flag.
+		kubectlVerb = "apply"
+	case "server-side-apply":
+		kubectlVerb = "apply"
+	}
+	args := []string{kubectlVerb}
+	if action == "delete" {
+		args = append(args, "--ignore-not-found")
+	}
+
+	manifestBytes, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifestBytes) == 0 && len(flags) == 0 {
+		return nil, fmt.Errorf("Must provide at least one of flags or manifest.")
+	}
+
+	mergeStrategy := we.Template.Resource.MergeStrategy
+	if mergeStrategy == "" {
+		mergeStrategy = "strategic"
+	}
+
+	switch action {
+	case "get", "delete":
+		args = append(args, flags...)
+	case "create":
+		args = append(args, "-f", manifestPath)
+		args = append(args, flags...)
+	case "apply":
+		args = append(args, "-f", manifestPath, "--validate=false")
+		args = append(args, flags...)
+	case "preview":
+		args = append(args, "-f", manifestPath, "--validate=false", "--dry-run=server", "-o", "json")
+		args = append(args, flags...)
+	case "server-side-apply":
+		args = append(args, "-f", manifestPath, "--server-side")
+		if we.Template.Resource.FieldManager != "" {
+			args = append(args, "--field-manager="+we.Template.Resource.FieldManager)
+		}
+		if we.Template.Resource.ForceConflicts {
+			args = append(args, "--force-conflicts")
+		}
+		if we.Template.Resource.Subresource != "" {
+			args = append(args, "--subresource="+we.Template.Resource.Subresource)
+		}
+		args = append(args, flags...)
+	case "patch":
+		args = []string{"patch", "--type", mergeStrategy, "-p", string(manifestBytes), "-o", "json"}
+	default:
+		args = append(args, "-f", manifestPath)
+		args = append(args, flags...)
+	}
+	return args, nil
+}
+
+// matchConditions evaluates the fetched object (as raw JSON) against the success and
+// failure label-selector-style conditions configured on the resource template.
+//
+// It returns finished=true only when neither the success nor the failure condition
+// could be evaluated to true yet, signaling the caller to keep polling.
+func matchConditions(jsonBytes []byte, successReqs labels.Requirements, failReqs labels.Requirements) (bool, error) {
+	ls := labelsFromJSON(jsonBytes, successReqs, failReqs)
+
+	if len(failReqs) > 0 {
+		failSelector := labels.NewSelector().Add(failReqs...)
+		if failSelector.Matches(ls) {
+			return false, fmt.Errorf("failure condition '%s' evaluated true", failSelector)
+		}
+	}
+	if len(successReqs) > 0 {
+		successSelector := labels.NewSelector().Add(successReqs...)
+		if successSelector.Matches(ls) {
+			return false, nil
+		}
+	}
+	return true, fmt.Errorf("Neither success condition nor the failure condition has been matched. Retrying...")
+}
+
+// labelsFromJSON extracts the dotted-path fields referenced by the given requirements
+// out of a (possibly truncated, in-flight) JSON document, using gjson so that a
+// response which hasn't finished streaming yet doesn't prevent us from reading the
+// fields we already have.
+func labelsFromJSON(jsonBytes []byte, reqSets ...labels.Requirements) labels.Set {
+	set := labels.Set{}
+	for _, reqs := range reqSets {
+		for _, req := range reqs {
+			res := gjson.GetBytes(jsonBytes, req.Key())
+			if res.Exists() {
+				set[req.Key()] = res.String()
+			}
+		}
+	}
+	return set
+}
+
+// checkResourceState polls the live object until a success or failure condition is met,
+// or the context is cancelled. clientset targets the cluster the object lives in, which
+// may be a remote cluster resolved from the resource template's KubeConfig rather than
+// the pod's in-cluster API server.
+func (we *WorkflowExecutor) checkResourceState(ctx context.Context, clientset kubernetes.Interface, selfLink string, successReqs labels.Requirements, failReqs labels.Requirements) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+
+		request := clientset.Discovery().RESTClient().Get().RequestURI(selfLink)
+		stream, err := request.Stream(ctx)
+		if err != nil {
+			return false, err
+		}
+		jsonBytes, err := ioutil.ReadAll(stream)
+		_ = stream.Close()
+		if err != nil {
+			return false, err
+		}
+
+		finished, err := matchConditions(jsonBytes, successReqs, failReqs)
+		if !finished {
+			return err == nil, err
+		}
+
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+// FieldConflict is a single field owned by another manager that blocked a server-side
+// apply.
+type FieldConflict struct {
+	Manager string `json:"manager"`
+	Path    string `json:"path"`
+}
+
+// ConflictReport is the structured node output surfaced when a "server-side-apply"
+// action fails because of a field conflict, so a caller can decide whether to retry with
+// ForceConflicts.
+type ConflictReport struct {
+	Conflicts []FieldConflict `json:"conflicts"`
+}
+
+var conflictManagerRe = regexp.MustCompile(`conflict(?:s)? with "([^"]+)"`)
+var conflictPathRe = regexp.MustCompile(`^\s*-?\s*\.?([A-Za-z0-9_.\[\]]+)\s*$`)
+
+// serverSideApplyResource runs `kubectl apply --server-side` with the template's
+// FieldManager/ForceConflicts/Subresource settings. When the apply is rejected because
+// of a field conflict, the conflicting field paths and owning managers are parsed out of
+// kubectl's stderr and returned as a structured node output instead of just an opaque
+// error.
+func (we *WorkflowExecutor) serverSideApplyResource(ctx context.Context, manifestPath string, flags []string, kubeConfig *materializedKubeConfig) (string, string, error) {
+	args, err := we.getKubectlArguments("server-side-apply", manifestPath, flags)
+	if err != nil {
+		return "", "", err
+	}
+	args = append(args, kubeConfig.kubectlFlags()...)
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if report := parseConflictError(stderr.String()); report != nil {
+			reportJSON, mErr := json.Marshal(report)
+			if mErr != nil {
+				return "", stderr.String(), mErr
+			}
+			return string(reportJSON), stderr.String(), fmt.Errorf("server-side apply conflicts with %d field(s) owned by another manager; set forceConflicts to override", len(report.Conflicts))
+		}
+		return "", stderr.String(), err
+	}
+
+	if err := we.checkConditions(ctx, manifestPath, kubeConfig); err != nil {
+		return string(out), "", err
+	}
+	if err := we.waitForResource(ctx, manifestPath, kubeConfig); err != nil {
+		return string(out), "", err
+	}
+	return string(out), "", nil
+}
+
+// parseConflictError scans kubectl's server-side apply conflict error text for the
+// owning manager and field paths it reports, returning nil if the error doesn't look
+// like a field conflict. A conflict error can report more than one manager, each
+// followed by the block of path lines it owns, so each path is attributed to the
+// manager header it actually appeared under rather than to the first manager in the
+// whole blob.
+func parseConflictError(stderr string) *ConflictReport {
+	report := &ConflictReport{}
+	currentManager := ""
+	for _, line := range splitLines(stderr) {
+		if managerMatch := conflictManagerRe.FindStringSubmatch(line); managerMatch != nil {
+			currentManager = managerMatch[1]
+			continue
+		}
+		if currentManager == "" {
+			continue
+		}
+		if pathMatch := conflictPathRe.FindStringSubmatch(line); pathMatch != nil && pathMatch[1] != "" {
+			report.Conflicts = append(report.Conflicts, FieldConflict{Manager: currentManager, Path: pathMatch[1]})
+		}
+	}
+	if len(report.Conflicts) == 0 {
+		return nil
+	}
+	return report
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i, r := range s {
+		if r == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+	lines = append(lines, s[start:])
+	return lines
+}
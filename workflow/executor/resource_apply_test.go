@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseConflictErrorSingleManager is the common case: one manager owning one or more
+// conflicting fields.
+func TestParseConflictErrorSingleManager(t *testing.T) {
+	stderr := `error: Apply failed with 2 conflicts: conflicts with "kubectl-client-side-apply":
+- .spec.replicas
+- .spec.template.spec.containers[0].image
+`
+	report := parseConflictError(stderr)
+	assert.NotNil(t, report)
+	assert.Equal(t, []FieldConflict{
+		{Manager: "kubectl-client-side-apply", Path: "spec.replicas"},
+		{Manager: "kubectl-client-side-apply", Path: "spec.template.spec.containers[0].image"},
+	}, report.Conflicts)
+}
+
+// TestParseConflictErrorMultipleManagers asserts that path lines are attributed to the
+// manager header immediately preceding them, not to whichever manager is mentioned
+// first in the error text.
+func TestParseConflictErrorMultipleManagers(t *testing.T) {
+	stderr := `error: Apply failed with 3 conflicts: conflicts with "argocd-controller":
+- .spec.replicas
+conflicts with "kubectl-client-side-apply":
+- .spec.template.spec.containers[0].image
+- .metadata.labels.app
+`
+	report := parseConflictError(stderr)
+	assert.NotNil(t, report)
+	assert.Equal(t, []FieldConflict{
+		{Manager: "argocd-controller", Path: "spec.replicas"},
+		{Manager: "kubectl-client-side-apply", Path: "spec.template.spec.containers[0].image"},
+		{Manager: "kubectl-client-side-apply", Path: "metadata.labels.app"},
+	}, report.Conflicts)
+}
+
+func TestParseConflictErrorNoConflict(t *testing.T) {
+	report := parseConflictError("error: some other unrelated failure")
+	assert.Nil(t, report)
+}
+
+func TestSplitLines(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitLines("a\nb\nc"))
+	assert.Equal(t, []string{""}, splitLines(""))
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+// AssertionResult is the outcome of a single assertion evaluated by the `assert` action,
+// exposed as a node output so a workflow can be used as a black-box conformance test.
+type AssertionResult struct {
+	Index       int    `json:"index"`
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	Message     string `json:"message,omitempty"`
+}
+
+// assertResource fetches the manifest's target object and evaluates every configured
+// assertion against it, aggregating all failures into a single error report instead of
+// failing fast on the first one.
+func (we *WorkflowExecutor) assertResource(ctx context.Context, manifestPath string, kubeConfig *materializedKubeConfig) (string, string, error) {
+	assertions := we.Template.Resource.Assertions
+
+	getArgs := append([]string{"get", "-f", manifestPath, "-o", "json"}, kubeConfig.kubectlFlags()...)
+	objJSON, getErr := exec.CommandContext(ctx, "kubectl", getArgs...).Output()
+	exists := getErr == nil
+
+	results := make([]AssertionResult, 0, len(assertions))
+	var failures []string
+	for i, a := range assertions {
+		res := evaluateAssertion(i, a, exists, objJSON)
+		results = append(results, res)
+		if !res.Passed {
+			failures = append(failures, fmt.Sprintf("assertion %d (%s): %s", res.Index, res.Description, res.Message))
+		}
+	}
+
+	resultJSON, err := json.Marshal(results)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(failures) > 0 {
+		return string(resultJSON), "", fmt.Errorf("%d of %d assertions failed:\n%s", len(failures), len(assertions), strings.Join(failures, "\n"))
+	}
+	return string(resultJSON), "", nil
+}
+
+// evaluateAssertion checks a single ResourceAssertion against the fetched object and
+// returns whether it passed.
+func evaluateAssertion(index int, a wfv1.ResourceAssertion, exists bool, objJSON []byte) AssertionResult {
+	switch {
+	case a.Exists != nil:
+		desc := fmt.Sprintf("exists=%v", *a.Exists)
+		if exists != *a.Exists {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("expected exists=%v, got exists=%v", *a.Exists, exists)}
+		}
+		return AssertionResult{Index: index, Description: desc, Passed: true}
+
+	case a.FieldEquals != nil:
+		desc := fmt.Sprintf("fieldEquals %s == %q", a.FieldEquals.Path, a.FieldEquals.Value)
+		res := gjson.GetBytes(objJSON, a.FieldEquals.Path)
+		if !res.Exists() {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("field %q not found", a.FieldEquals.Path)}
+		}
+		if res.String() != a.FieldEquals.Value {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("expected %q, got %q", a.FieldEquals.Value, res.String())}
+		}
+		return AssertionResult{Index: index, Description: desc, Passed: true}
+
+	case a.FieldMatches != nil:
+		desc := fmt.Sprintf("fieldMatches %s ~= %q", a.FieldMatches.Path, a.FieldMatches.Regex)
+		res := gjson.GetBytes(objJSON, a.FieldMatches.Path)
+		if !res.Exists() {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("field %q not found", a.FieldMatches.Path)}
+		}
+		matched, err := regexp.MatchString(a.FieldMatches.Regex, res.String())
+		if err != nil {
+			return AssertionResult{Index: index, Description: desc, Message: err.Error()}
+		}
+		if !matched {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("value %q did not match regex %q", res.String(), a.FieldMatches.Regex)}
+		}
+		return AssertionResult{Index: index, Description: desc, Passed: true}
+
+	case a.Count != nil:
+		op := a.Count.Op
+		if op == "" {
+			op = "=="
+		}
+		desc := fmt.Sprintf("count %s %s %d", a.Count.Path, op, a.Count.Value)
+		res := gjson.GetBytes(objJSON, a.Count.Path)
+		n := 0
+		switch {
+		case res.IsArray():
+			n = len(res.Array())
+		case res.Exists():
+			n = 1
+		}
+		if !compareCount(n, op, a.Count.Value) {
+			return AssertionResult{Index: index, Description: desc, Message: fmt.Sprintf("count was %d", n)}
+		}
+		return AssertionResult{Index: index, Description: desc, Passed: true}
+
+	default:
+		return AssertionResult{Index: index, Description: "empty assertion", Message: "no assertion field was set"}
+	}
+}
+
+func compareCount(actual int, op string, expected int) bool {
+	switch op {
+	case "==":
+		return actual == expected
+	case "!=":
+		return actual != expected
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	default:
+		return false
+	}
+}
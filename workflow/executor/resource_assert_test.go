@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestEvaluateAssertionExists(t *testing.T) {
+	res := evaluateAssertion(0, wfv1.ResourceAssertion{Exists: boolPtr(true)}, true, nil)
+	assert.True(t, res.Passed)
+
+	res = evaluateAssertion(0, wfv1.ResourceAssertion{Exists: boolPtr(true)}, false, nil)
+	assert.False(t, res.Passed)
+
+	res = evaluateAssertion(0, wfv1.ResourceAssertion{Exists: boolPtr(false)}, false, nil)
+	assert.True(t, res.Passed)
+}
+
+func TestEvaluateAssertionFieldEquals(t *testing.T) {
+	objJSON := []byte(`{"status":{"phase":"Running"}}`)
+	a := wfv1.ResourceAssertion{FieldEquals: &wfv1.FieldEqualsAssertion{Path: "status.phase", Value: "Running"}}
+	res := evaluateAssertion(0, a, true, objJSON)
+	assert.True(t, res.Passed)
+
+	a = wfv1.ResourceAssertion{FieldEquals: &wfv1.FieldEqualsAssertion{Path: "status.phase", Value: "Pending"}}
+	res = evaluateAssertion(0, a, true, objJSON)
+	assert.False(t, res.Passed)
+	assert.Contains(t, res.Message, "expected")
+
+	a = wfv1.ResourceAssertion{FieldEquals: &wfv1.FieldEqualsAssertion{Path: "status.missing", Value: "x"}}
+	res = evaluateAssertion(0, a, true, objJSON)
+	assert.False(t, res.Passed)
+	assert.Contains(t, res.Message, "not found")
+}
+
+func TestEvaluateAssertionFieldMatches(t *testing.T) {
+	objJSON := []byte(`{"status":{"podIP":"10.0.0.5"}}`)
+	a := wfv1.ResourceAssertion{FieldMatches: &wfv1.FieldMatchesAssertion{Path: "status.podIP", Regex: `^10\.`}}
+	res := evaluateAssertion(0, a, true, objJSON)
+	assert.True(t, res.Passed)
+
+	a = wfv1.ResourceAssertion{FieldMatches: &wfv1.FieldMatchesAssertion{Path: "status.podIP", Regex: `^192\.`}}
+	res = evaluateAssertion(0, a, true, objJSON)
+	assert.False(t, res.Passed)
+}
+
+func TestEvaluateAssertionCount(t *testing.T) {
+	objJSON := []byte(`{"status":{"containerStatuses":[{"ready":true},{"ready":false}]}}`)
+	a := wfv1.ResourceAssertion{Count: &wfv1.CountAssertion{Path: "status.containerStatuses", Op: "==", Value: 2}}
+	res := evaluateAssertion(0, a, true, objJSON)
+	assert.True(t, res.Passed)
+
+	a = wfv1.ResourceAssertion{Count: &wfv1.CountAssertion{Path: "status.containerStatuses", Op: ">", Value: 5}}
+	res = evaluateAssertion(0, a, true, objJSON)
+	assert.False(t, res.Passed)
+
+	// Op defaults to "==" when empty
+	a = wfv1.ResourceAssertion{Count: &wfv1.CountAssertion{Path: "status.containerStatuses", Value: 2}}
+	res = evaluateAssertion(0, a, true, objJSON)
+	assert.True(t, res.Passed)
+}
+
+func TestEvaluateAssertionEmpty(t *testing.T) {
+	res := evaluateAssertion(0, wfv1.ResourceAssertion{}, true, nil)
+	assert.False(t, res.Passed)
+	assert.Equal(t, "empty assertion", res.Description)
+}
+
+func TestCompareCount(t *testing.T) {
+	assert.True(t, compareCount(3, "==", 3))
+	assert.True(t, compareCount(3, "!=", 4))
+	assert.True(t, compareCount(3, "<", 4))
+	assert.True(t, compareCount(3, "<=", 3))
+	assert.True(t, compareCount(3, ">", 2))
+	assert.True(t, compareCount(3, ">=", 3))
+	assert.False(t, compareCount(3, "unknown", 3))
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"k8s.io/client-go/kubernetes"
+)
+
+// checkConditions polls the manifest's target object against the resource template's
+// SuccessCondition/FailureCondition, if either is set, and returns once one of them is
+// matched (or an error if neither ever is). It is a no-op when neither condition is
+// configured, independent of WaitFor.
+func (we *WorkflowExecutor) checkConditions(ctx context.Context, manifestPath string, kubeConfig *materializedKubeConfig) error {
+	successReqs, failReqs, err := we.conditionRequirements()
+	if err != nil {
+		return err
+	}
+	if len(successReqs) == 0 && len(failReqs) == 0 {
+		return nil
+	}
+
+	clientset, err := we.resourceClientSet(kubeConfig)
+	if err != nil {
+		return err
+	}
+	selfLink, err := we.resourceSelfLink(manifestPath, clientset)
+	if err != nil {
+		return err
+	}
+
+	_, err = we.checkResourceState(ctx, clientset, selfLink, successReqs, failReqs)
+	return err
+}
+
+// resourceSelfLink derives the legacy selfLink-style REST path of the manifest's target
+// object by mapping its Kind to a resource name (and namespaced-ness) via the discovery
+// client, so checkResourceState can poll it directly over the generic REST client.
+func (we *WorkflowExecutor) resourceSelfLink(manifestPath string, clientset kubernetes.Interface) (string, error) {
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return "", err
+	}
+	apiVersion := gjson.GetBytes(data, "apiVersion").String()
+	kind := gjson.GetBytes(data, "kind").String()
+	name := gjson.GetBytes(data, "metadata.name").String()
+	namespace := gjson.GetBytes(data, "metadata.namespace").String()
+	if namespace == "" {
+		namespace = we.Namespace
+	}
+	if apiVersion == "" || kind == "" || name == "" {
+		return "", fmt.Errorf("manifest is missing apiVersion, kind or metadata.name")
+	}
+
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(apiVersion)
+	if err != nil {
+		return "", err
+	}
+	var plural string
+	var namespaced bool
+	for _, r := range resources.APIResources {
+		if r.Kind == kind {
+			plural = r.Name
+			namespaced = r.Namespaced
+			break
+		}
+	}
+	if plural == "" {
+		return "", fmt.Errorf("%s/%s is not served by the target cluster", apiVersion, kind)
+	}
+
+	group, version := splitAPIVersion(apiVersion)
+	base := fmt.Sprintf("/api/%s", version)
+	if group != "" {
+		base = fmt.Sprintf("/apis/%s/%s", group, version)
+	}
+	if namespaced {
+		return fmt.Sprintf("%s/namespaces/%s/%s/%s", base, namespace, plural, name), nil
+	}
+	return fmt.Sprintf("%s/%s/%s", base, plural, name), nil
+}
+
+// splitAPIVersion splits "group/version" into its parts, or returns ("", version) for
+// the core group's bare "version" form (e.g. "v1").
+func splitAPIVersion(apiVersion string) (group string, version string) {
+	if idx := strings.Index(apiVersion, "/"); idx >= 0 {
+		return apiVersion[:idx], apiVersion[idx+1:]
+	}
+	return "", apiVersion
+}
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/executor/mocks"
+)
+
+func TestSplitAPIVersion(t *testing.T) {
+	group, version := splitAPIVersion("v1")
+	assert.Equal(t, "", group)
+	assert.Equal(t, "v1", version)
+
+	group, version = splitAPIVersion("apps/v1")
+	assert.Equal(t, "apps", group)
+	assert.Equal(t, "v1", version)
+}
+
+func writeTempManifest(t *testing.T, contents string) string {
+	t.Helper()
+	f, err := ioutil.TempFile("/tmp", "resource-conditions-manifest")
+	assert.NoError(t, err)
+	_, err = f.WriteString(contents)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	t.Cleanup(func() { _ = os.Remove(f.Name()) })
+	return f.Name()
+}
+
+func TestResourceSelfLinkNamespaced(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+			},
+		},
+	}
+	we := &WorkflowExecutor{Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	selfLink, err := we.resourceSelfLink(manifestPath, fakeClientset)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/namespaces/"+fakeNamespace+"/configmaps/my-cm", selfLink)
+}
+
+func TestResourceSelfLinkClusterScoped(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "namespaces", Kind: "Namespace", Namespaced: false},
+			},
+		},
+	}
+	we := &WorkflowExecutor{Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"my-ns"}}`)
+
+	selfLink, err := we.resourceSelfLink(manifestPath, fakeClientset)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/namespaces/my-ns", selfLink)
+}
+
+func TestResourceSelfLinkGroupAPI(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			},
+		},
+	}
+	we := &WorkflowExecutor{Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"my-deploy"}}`)
+
+	selfLink, err := we.resourceSelfLink(manifestPath, fakeClientset)
+	assert.NoError(t, err)
+	assert.Equal(t, "/apis/apps/v1/namespaces/"+fakeNamespace+"/deployments/my-deploy", selfLink)
+}
+
+func TestResourceSelfLinkUnknownKind(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.Resources = []*metav1.APIResourceList{{GroupVersion: "v1", APIResources: nil}}
+	we := &WorkflowExecutor{Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"Bogus","metadata":{"name":"x"}}`)
+
+	_, err := we.resourceSelfLink(manifestPath, fakeClientset)
+	assert.Error(t, err)
+}
+
+func TestResourceSelfLinkMissingFields(t *testing.T) {
+	we := &WorkflowExecutor{Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1"}`)
+	_, err := we.resourceSelfLink(manifestPath, fake.NewSimpleClientset())
+	assert.Error(t, err)
+}
+
+// TestCheckConditionsNoopWithoutConditions asserts that checkConditions does nothing --
+// and in particular never needs a working clientset/discovery -- when neither
+// SuccessCondition nor FailureCondition is configured.
+func TestCheckConditionsNoopWithoutConditions(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+	err := we.checkConditions(context.Background(), manifestPath, nil)
+	assert.NoError(t, err)
+}
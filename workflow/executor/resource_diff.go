@@ -0,0 +1,183 @@
+package executor
+
+import "encoding/json"
+
+// ResourceDiff is the machine-readable change list produced by a `preview` resource
+// step: whether the object would be created, updated or left as-is, plus the
+// field-level paths that would change.
+type ResourceDiff struct {
+	// ChangeType is one of "create", "update" or "noop"
+	ChangeType string `json:"changeType"`
+
+	// Added is the set of field paths present in the proposed object but not the live one
+	Added []string `json:"added,omitempty"`
+
+	// Removed is the set of field paths present in the live object but not the proposed one
+	Removed []string `json:"removed,omitempty"`
+
+	// Changed is the set of field paths whose value would change
+	Changed []FieldDiff `json:"changed,omitempty"`
+}
+
+// FieldDiff describes a single field-level change between the live and proposed object
+type FieldDiff struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// computeResourceDiff compares the live object (nil/empty if it doesn't exist yet) against
+// the proposed object returned by a dry-run apply, and produces a structured, field-level
+// change list.
+func computeResourceDiff(liveJSON []byte, proposedJSON []byte) (*ResourceDiff, error) {
+	var proposed map[string]interface{}
+	if err := json.Unmarshal(proposedJSON, &proposed); err != nil {
+		return nil, err
+	}
+	proposed = stripServerManagedFields(proposed)
+
+	if len(liveJSON) == 0 {
+		return &ResourceDiff{
+			ChangeType: "create",
+			Added:      flattenPaths("", proposed),
+		}, nil
+	}
+
+	var live map[string]interface{}
+	if err := json.Unmarshal(liveJSON, &live); err != nil {
+		return nil, err
+	}
+	live = stripServerManagedFields(live)
+
+	diff := &ResourceDiff{ChangeType: "noop"}
+	diffObjects("", live, proposed, diff)
+	if len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Changed) > 0 {
+		diff.ChangeType = "update"
+	}
+	return diff, nil
+}
+
+// serverManagedMetadataFields are metadata bookkeeping the API server stamps onto every
+// object, regardless of what the caller submitted. They change on every mutation (or
+// differ between a live object and a freshly rendered dry-run response) without
+// reflecting any drift the workflow author actually cares about.
+var serverManagedMetadataFields = []string{
+	"resourceVersion", "generation", "managedFields", "uid", "creationTimestamp", "selfLink",
+}
+
+// lastAppliedConfigAnnotation is injected by a client-side `kubectl apply --dry-run=client`
+// fallback and would otherwise always show up as an added/changed field.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// stripServerManagedFields returns a shallow copy of obj with status and the
+// server/client bookkeeping under metadata removed, so a preview diff reflects only
+// drift the workflow author actually configured, not API server noise.
+func stripServerManagedFields(obj map[string]interface{}) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		clone[k] = v
+	}
+	delete(clone, "status")
+
+	metadata, ok := clone["metadata"].(map[string]interface{})
+	if !ok {
+		return clone
+	}
+	metaClone := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		metaClone[k] = v
+	}
+	for _, f := range serverManagedMetadataFields {
+		delete(metaClone, f)
+	}
+	if annotations, ok := metaClone["annotations"].(map[string]interface{}); ok {
+		annClone := make(map[string]interface{}, len(annotations))
+		for k, v := range annotations {
+			annClone[k] = v
+		}
+		delete(annClone, lastAppliedConfigAnnotation)
+		if len(annClone) == 0 {
+			delete(metaClone, "annotations")
+		} else {
+			metaClone["annotations"] = annClone
+		}
+	}
+	clone["metadata"] = metaClone
+	return clone
+}
+
+// flattenPaths returns the dotted field paths of every leaf value in obj
+func flattenPaths(prefix string, obj map[string]interface{}) []string {
+	var paths []string
+	for k, v := range obj {
+		path := joinPath(prefix, k)
+		if child, ok := v.(map[string]interface{}); ok {
+			paths = append(paths, flattenPaths(path, child)...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// diffObjects recursively compares live and proposed, appending any added, removed or
+// changed field paths to diff.
+func diffObjects(prefix string, live, proposed map[string]interface{}, diff *ResourceDiff) {
+	for k, proposedVal := range proposed {
+		path := joinPath(prefix, k)
+		liveVal, exists := live[k]
+		if !exists {
+			if child, ok := proposedVal.(map[string]interface{}); ok {
+				diff.Added = append(diff.Added, flattenPaths(path, child)...)
+			} else {
+				diff.Added = append(diff.Added, path)
+			}
+			continue
+		}
+		liveChild, liveIsMap := liveVal.(map[string]interface{})
+		proposedChild, proposedIsMap := proposedVal.(map[string]interface{})
+		switch {
+		case liveIsMap && proposedIsMap:
+			diffObjects(path, liveChild, proposedChild, diff)
+		case deepEqual(liveVal, proposedVal):
+			// no-op
+		default:
+			diff.Changed = append(diff.Changed, FieldDiff{Path: path, From: liveVal, To: proposedVal})
+		}
+	}
+	for k, liveVal := range live {
+		if _, exists := proposed[k]; exists {
+			continue
+		}
+		path := joinPath(prefix, k)
+		if child, ok := liveVal.(map[string]interface{}); ok {
+			diff.Removed = append(diff.Removed, flattenPaths(path, child)...)
+		} else {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// deepEqual compares two decoded JSON values (string, float64, bool, nil, []interface{},
+// map[string]interface{}) for equality.
+func deepEqual(a, b interface{}) bool {
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aBytes) == string(bBytes)
+}
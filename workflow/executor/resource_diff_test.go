@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComputeResourceDiffIgnoresBookkeepingNoise asserts that server/client-injected
+// bookkeeping fields (resourceVersion, managedFields, status, the last-applied-config
+// annotation, etc) never show up as drift on an otherwise untouched object.
+func TestComputeResourceDiffIgnoresBookkeepingNoise(t *testing.T) {
+	live := []byte(`{
+		"metadata": {
+			"name": "my-cm",
+			"resourceVersion": "111",
+			"generation": 1,
+			"uid": "abc-123",
+			"creationTimestamp": "2020-01-01T00:00:00Z",
+			"selfLink": "/api/v1/namespaces/default/configmaps/my-cm",
+			"managedFields": [{"manager": "kubectl"}],
+			"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+		},
+		"data": {"key": "value"},
+		"status": {"phase": "Active"}
+	}`)
+	proposed := []byte(`{
+		"metadata": {
+			"name": "my-cm",
+			"resourceVersion": "999",
+			"generation": 2,
+			"uid": "abc-123",
+			"creationTimestamp": "2020-01-01T00:00:00Z",
+			"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"data\":{\"key\":\"value\"}}"}
+		},
+		"data": {"key": "value"}
+	}`)
+
+	diff, err := computeResourceDiff(live, proposed)
+	assert.NoError(t, err)
+	assert.Equal(t, "noop", diff.ChangeType)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Changed)
+}
+
+// TestComputeResourceDiffDetectsRealChange asserts a genuine spec-level change is still
+// reported once the bookkeeping noise is stripped out.
+func TestComputeResourceDiffDetectsRealChange(t *testing.T) {
+	live := []byte(`{"metadata": {"name": "my-cm", "resourceVersion": "1"}, "data": {"key": "old"}}`)
+	proposed := []byte(`{"metadata": {"name": "my-cm"}, "data": {"key": "new"}}`)
+
+	diff, err := computeResourceDiff(live, proposed)
+	assert.NoError(t, err)
+	assert.Equal(t, "update", diff.ChangeType)
+	assert.Equal(t, []FieldDiff{{Path: "data.key", From: "old", To: "new"}}, diff.Changed)
+}
+
+// TestComputeResourceDiffCreate asserts a nil live object is reported as a create, with
+// bookkeeping noise stripped from the proposed object's added paths too.
+func TestComputeResourceDiffCreate(t *testing.T) {
+	proposed := []byte(`{"metadata": {"name": "my-cm", "resourceVersion": "999"}, "data": {"key": "value"}}`)
+
+	diff, err := computeResourceDiff(nil, proposed)
+	assert.NoError(t, err)
+	assert.Equal(t, "create", diff.ChangeType)
+	assert.ElementsMatch(t, []string{"metadata.name", "data.key"}, diff.Added)
+}
+
+func TestStripServerManagedFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "foo",
+			"resourceVersion": "1",
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"keep-me": "yes",
+			},
+		},
+		"status": map[string]interface{}{"phase": "Active"},
+		"spec":   map[string]interface{}{"replicas": 1},
+	}
+
+	stripped := stripServerManagedFields(obj)
+	_, hasStatus := stripped["status"]
+	assert.False(t, hasStatus)
+	metadata := stripped["metadata"].(map[string]interface{})
+	_, hasResourceVersion := metadata["resourceVersion"]
+	assert.False(t, hasResourceVersion)
+	annotations := metadata["annotations"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"keep-me": "yes"}, annotations)
+
+	// original input must not be mutated
+	_, origHasStatus := obj["status"]
+	assert.True(t, origHasStatus)
+}
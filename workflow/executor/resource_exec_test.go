@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/executor/mocks"
+)
+
+// withFakeKubectl puts a stub `kubectl` executable at the front of PATH for the
+// duration of the test, so ExecResource's exec.CommandContext("kubectl", ...) calls run
+// against canned behavior instead of a real cluster.
+func withFakeKubectl(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kubectl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestGetKubectlArgumentsPreview(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	args, err := we.getKubectlArguments("preview", manifestPath, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apply", "-f", manifestPath, "--validate=false", "--dry-run=server", "-o", "json"}, args)
+}
+
+func TestGetKubectlArgumentsServerSideApply(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			FieldManager:   "my-manager",
+			ForceConflicts: true,
+			Subresource:    "status",
+		}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	args, err := we.getKubectlArguments("server-side-apply", manifestPath, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"apply", "-f", manifestPath, "--server-side",
+		"--field-manager=my-manager", "--force-conflicts", "--subresource=status",
+	}, args)
+}
+
+func TestGetKubectlArgumentsServerSideApplyDefaults(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	args, err := we.getKubectlArguments("server-side-apply", manifestPath, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apply", "-f", manifestPath, "--server-side"}, args)
+}
+
+// TestExecResourceCreate covers the plain create/apply/replace/patch dispatch path: no
+// LintPolicy, no SuccessCondition/FailureCondition and no WaitFor configured, so
+// ExecResource should just run kubectl and return its output.
+func TestExecResourceCreate(t *testing.T) {
+	withFakeKubectl(t, "#!/bin/sh\necho '{\"apiVersion\":\"v1\",\"kind\":\"ConfigMap\",\"metadata\":{\"name\":\"my-cm\"}}'\n")
+
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		ClientSet:       fake.NewSimpleClientset(),
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	out, stderr, err := we.ExecResource(context.Background(), "create", manifestPath, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, stderr)
+	assert.Contains(t, out, "my-cm")
+}
+
+// TestExecResourceLintErrorBlocksMutation asserts that a LintSeverityError finding short
+// circuits ExecResource before the mutating kubectl action is ever invoked -- there is
+// deliberately no fake kubectl on PATH here, so the test would fail with "executable
+// file not found" if the lint gate were bypassed.
+func TestExecResourceLintErrorBlocksMutation(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			LintPolicy: &wfv1.LintPolicy{PrivilegedOrHostNamespace: wfv1.LintSeverityError},
+		}},
+		ClientSet:       fake.NewSimpleClientset(),
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "my-pod"},
+		"spec": {"containers": [{"name": "main", "securityContext": {"privileged": true}}]}
+	}`)
+
+	_, _, err := we.ExecResource(context.Background(), "create", manifestPath, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "privileged")
+}
+
+// TestExecResourcePreview covers the preview dispatch branch end-to-end: a dry-run apply
+// followed by a `get` to fetch the live object, diffed via computeResourceDiff.
+func TestExecResourcePreview(t *testing.T) {
+	withFakeKubectl(t, `#!/bin/sh
+case "$1" in
+  apply) echo '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"},"data":{"key":"new"}}' ;;
+  get) echo '{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"},"data":{"key":"old"}}' ;;
+esac
+`)
+
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			// the proposed object's data.key always matches, so this is satisfied
+			// regardless of what the diff itself finds.
+			SuccessCondition: "data.key == new",
+		}},
+		ClientSet:       fake.NewSimpleClientset(),
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"},"data":{"key":"new"}}`)
+
+	out, _, err := we.ExecResource(context.Background(), "preview", manifestPath, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"changeType":"update"`)
+	assert.Contains(t, out, "data.key")
+}
+
+// TestExecResourceUnknownAction exercises the fallback branch of ExecResource's dispatch
+// (anything that isn't preview/assert/server-side-apply) for an action with no special
+// case in getKubectlArguments either.
+func TestExecResourceUnknownAction(t *testing.T) {
+	withFakeKubectl(t, "#!/bin/sh\necho 'args:' \"$@\"\n")
+
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		ClientSet:       fake.NewSimpleClientset(),
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	manifestPath := writeTempManifest(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-cm"}}`)
+
+	out, _, err := we.ExecResource(context.Background(), "label", manifestPath, []string{"-o", "yaml"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "-o yaml")
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// materializedKubeConfig is the resolved location (and optional context override) of the
+// kubeconfig a resource step should use to target an external cluster.
+type materializedKubeConfig struct {
+	Path    string
+	Context string
+}
+
+// materializeKubeConfig resolves the resource template's KubeConfig, if any, to a file on
+// disk with tight (0600) permissions, and returns a cleanup func that removes it once the
+// step is done. A KubeConfig backed by an already-mounted VolumePath is returned as-is
+// with a no-op cleanup; one backed by a SecretRef is fetched and written out ourselves.
+func (we *WorkflowExecutor) materializeKubeConfig(ctx context.Context) (*materializedKubeConfig, func(), error) {
+	noop := func() {}
+	kc := we.Template.Resource.KubeConfig
+	if kc == nil {
+		return nil, noop, nil
+	}
+
+	if kc.VolumePath != "" {
+		return &materializedKubeConfig{Path: kc.VolumePath, Context: kc.Context}, noop, nil
+	}
+
+	if kc.SecretRef == nil {
+		return nil, noop, fmt.Errorf("resource kubeConfig must set either secretRef or volumePath")
+	}
+
+	secret, err := we.ClientSet.CoreV1().Secrets(we.Namespace).Get(ctx, kc.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, noop, err
+	}
+	data, ok := secret.Data[kc.SecretRef.Key]
+	if !ok {
+		return nil, noop, fmt.Errorf("secret %s/%s has no key %q", we.Namespace, kc.SecretRef.Name, kc.SecretRef.Key)
+	}
+
+	tmpFile, err := ioutil.TempFile("", "argo-kubeconfig-")
+	if err != nil {
+		return nil, noop, err
+	}
+	path := tmpFile.Name()
+	cleanup := func() { _ = os.Remove(path) }
+
+	if err := os.Chmod(path, 0600); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		cleanup()
+		return nil, noop, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+
+	return &materializedKubeConfig{Path: path, Context: kc.Context}, cleanup, nil
+}
+
+// kubectlFlags returns the --kubeconfig/--context flags to pass to kubectl for this
+// materialized kubeconfig, or nil when the step targets the in-cluster API server.
+func (kc *materializedKubeConfig) kubectlFlags() []string {
+	if kc == nil {
+		return nil
+	}
+	flags := []string{"--kubeconfig", kc.Path}
+	if kc.Context != "" {
+		flags = append(flags, "--context", kc.Context)
+	}
+	return flags
+}
+
+// resourceClientSet returns the clientset that should be used to poll this resource
+// step's success/failure conditions: the in-cluster ClientSet by default, or one built
+// from the resource template's KubeConfig when the step targets a remote cluster.
+func (we *WorkflowExecutor) resourceClientSet(kubeConfig *materializedKubeConfig) (kubernetes.Interface, error) {
+	if kubeConfig == nil {
+		return we.ClientSet, nil
+	}
+	return kubeConfig.clientset()
+}
+
+// clientset builds a Kubernetes clientset targeting this kubeconfig, so that
+// checkResourceState polls success/failure conditions against the remote cluster instead
+// of the pod's in-cluster API server.
+func (kc *materializedKubeConfig) clientset() (kubernetes.Interface, error) {
+	if kc == nil {
+		return nil, nil
+	}
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kc.Path},
+		&clientcmd.ConfigOverrides{CurrentContext: kc.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/executor/mocks"
+)
+
+func TestMaterializeKubeConfigNil(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template:        wfv1.Template{Resource: &wfv1.ResourceTemplate{}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	kc, cleanup, err := we.materializeKubeConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, kc)
+	cleanup()
+}
+
+func TestMaterializeKubeConfigVolumePath(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			KubeConfig: &wfv1.ResourceKubeConfig{VolumePath: "/tmp/kubeconfig", Context: "my-context"},
+		}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	kc, cleanup, err := we.materializeKubeConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/kubeconfig", kc.Path)
+	assert.Equal(t, "my-context", kc.Context)
+	cleanup()
+}
+
+func TestMaterializeKubeConfigSecretRef(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-kubeconfig", Namespace: fakeNamespace},
+		Data:       map[string][]byte{"config": []byte("fake-kubeconfig-contents")},
+	})
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			KubeConfig: &wfv1.ResourceKubeConfig{SecretRef: &wfv1.SecretKeySelector{Name: "my-kubeconfig", Key: "config"}},
+		}},
+		ClientSet:       fakeClientset,
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	kc, cleanup, err := we.materializeKubeConfig(context.Background())
+	assert.NoError(t, err)
+	defer cleanup()
+	assert.FileExists(t, kc.Path)
+	contents, err := os.ReadFile(kc.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "fake-kubeconfig-contents", string(contents))
+
+	info, err := os.Stat(kc.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	cleanup()
+	_, err = os.Stat(kc.Path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMaterializeKubeConfigSecretRefMissingKey(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-kubeconfig", Namespace: fakeNamespace},
+		Data:       map[string][]byte{"other-key": []byte("x")},
+	})
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			KubeConfig: &wfv1.ResourceKubeConfig{SecretRef: &wfv1.SecretKeySelector{Name: "my-kubeconfig", Key: "config"}},
+		}},
+		ClientSet:       fakeClientset,
+		Namespace:       fakeNamespace,
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	_, _, err := we.materializeKubeConfig(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMaterializeKubeConfigNeitherSet(t *testing.T) {
+	we := &WorkflowExecutor{
+		Template: wfv1.Template{Resource: &wfv1.ResourceTemplate{
+			KubeConfig: &wfv1.ResourceKubeConfig{},
+		}},
+		RuntimeExecutor: &mocks.ContainerRuntimeExecutor{},
+	}
+	_, _, err := we.materializeKubeConfig(context.Background())
+	assert.Error(t, err)
+}
+
+func TestKubectlFlags(t *testing.T) {
+	var nilKC *materializedKubeConfig
+	assert.Nil(t, nilKC.kubectlFlags())
+
+	kc := &materializedKubeConfig{Path: "/tmp/kc"}
+	assert.Equal(t, []string{"--kubeconfig", "/tmp/kc"}, kc.kubectlFlags())
+
+	kc = &materializedKubeConfig{Path: "/tmp/kc", Context: "ctx"}
+	assert.Equal(t, []string{"--kubeconfig", "/tmp/kc", "--context", "ctx"}, kc.kubectlFlags())
+}
+
+func TestResourceClientSetDefaultsToInCluster(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	we := &WorkflowExecutor{ClientSet: fakeClientset, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	cs, err := we.resourceClientSet(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, fakeClientset, cs)
+}
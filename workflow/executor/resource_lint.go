@@ -0,0 +1,281 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+)
+
+// LintFinding is a single pre-flight lint result. Findings are logged; they are not
+// exposed as node outputs.
+type LintFinding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// lintManifest runs the resource template's LintPolicy checks over the manifest. It
+// returns every finding (including "warn" ones, which are only logged) and an error if
+// any check configured at LintSeverityError fired, so the step can be failed before any
+// cluster mutation happens.
+func (we *WorkflowExecutor) lintManifest(ctx context.Context, manifestPath string, kubeConfig *materializedKubeConfig) ([]LintFinding, error) {
+	policy := we.Template.Resource.LintPolicy
+	if policy == nil {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	objJSON, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	var findings []LintFinding
+	findings = append(findings, lintContainers(obj, policy)...)
+	if f := lintNamespaceMismatch(objJSON, we.Namespace, policy); f != nil {
+		findings = append(findings, *f)
+	}
+	if f := we.lintDeprecatedAPIVersion(ctx, objJSON, policy, kubeConfig); f != nil {
+		findings = append(findings, *f)
+	}
+
+	var errs []string
+	for _, f := range findings {
+		log.Warnf("lint %s [%s]: %s", f.Check, f.Severity, f.Message)
+		if f.Severity == string(wfv1.LintSeverityError) {
+			errs = append(errs, fmt.Sprintf("%s: %s", f.Check, f.Message))
+		}
+	}
+	if len(errs) > 0 {
+		return findings, fmt.Errorf("manifest failed lint checks:\n%s", strings.Join(errs, "\n"))
+	}
+	return findings, nil
+}
+
+func lintRun(sev wfv1.LintSeverity) bool {
+	return sev == wfv1.LintSeverityWarn || sev == wfv1.LintSeverityError
+}
+
+// lintContainers walks every container/initContainer in the decoded manifest (wherever
+// it's nested -- a bare Pod, or a Deployment/Job/etc's pod template) and runs the
+// per-container checks against each one.
+func lintContainers(obj interface{}, policy *wfv1.LintPolicy) []LintFinding {
+	var findings []LintFinding
+	for _, c := range findContainers(obj) {
+		name, _ := c["name"].(string)
+		if lintRun(policy.MissingResourceLimits) {
+			if f := lintResourceLimits(name, c, policy.MissingResourceLimits); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+		if lintRun(policy.LatestImageTag) {
+			if f := lintImageTag(name, c, policy.LatestImageTag); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+		if lintRun(policy.PrivilegedOrHostNamespace) {
+			if f := lintPrivileged(name, c, policy.PrivilegedOrHostNamespace); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+		if lintRun(policy.MissingProbes) {
+			if f := lintProbes(name, c, policy.MissingProbes); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+	}
+	if lintRun(policy.PrivilegedOrHostNamespace) {
+		findings = append(findings, lintHostNamespaces(obj, policy.PrivilegedOrHostNamespace)...)
+	}
+	return findings
+}
+
+func findContainers(obj interface{}) []map[string]interface{} {
+	var results []map[string]interface{}
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for _, key := range []string{"containers", "initContainers"} {
+			if arr, ok := v[key].([]interface{}); ok {
+				for _, c := range arr {
+					if cm, ok := c.(map[string]interface{}); ok {
+						results = append(results, cm)
+					}
+				}
+			}
+		}
+		for _, val := range v {
+			results = append(results, findContainers(val)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			results = append(results, findContainers(item)...)
+		}
+	}
+	return results
+}
+
+func lintResourceLimits(name string, c map[string]interface{}, sev wfv1.LintSeverity) *LintFinding {
+	resources, _ := c["resources"].(map[string]interface{})
+	_, hasRequests := resources["requests"]
+	_, hasLimits := resources["limits"]
+	if hasRequests && hasLimits {
+		return nil
+	}
+	return &LintFinding{
+		Check:    "missingResourceLimits",
+		Severity: string(sev),
+		Message:  fmt.Sprintf("container %q has no resources.requests/limits set", name),
+	}
+}
+
+func lintImageTag(name string, c map[string]interface{}, sev wfv1.LintSeverity) *LintFinding {
+	image, _ := c["image"].(string)
+	tag := ""
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		tag = image[idx+1:]
+	}
+	if tag != "" && tag != "latest" {
+		return nil
+	}
+	return &LintFinding{
+		Check:    "latestImageTag",
+		Severity: string(sev),
+		Message:  fmt.Sprintf("container %q uses image %q with the \"latest\" tag (or no tag at all)", name, image),
+	}
+}
+
+func lintPrivileged(name string, c map[string]interface{}, sev wfv1.LintSeverity) *LintFinding {
+	securityContext, _ := c["securityContext"].(map[string]interface{})
+	privileged, _ := securityContext["privileged"].(bool)
+	if !privileged {
+		return nil
+	}
+	return &LintFinding{
+		Check:    "privilegedOrHostNamespace",
+		Severity: string(sev),
+		Message:  fmt.Sprintf("container %q runs privileged", name),
+	}
+}
+
+func lintProbes(name string, c map[string]interface{}, sev wfv1.LintSeverity) *LintFinding {
+	_, hasReadiness := c["readinessProbe"]
+	_, hasLiveness := c["livenessProbe"]
+	if hasReadiness && hasLiveness {
+		return nil
+	}
+	missing := []string{}
+	if !hasReadiness {
+		missing = append(missing, "readinessProbe")
+	}
+	if !hasLiveness {
+		missing = append(missing, "livenessProbe")
+	}
+	return &LintFinding{
+		Check:    "missingProbes",
+		Severity: string(sev),
+		Message:  fmt.Sprintf("container %q is missing %s", name, strings.Join(missing, " and ")),
+	}
+}
+
+// lintHostNamespaces looks for a pod spec (wherever it's nested) using hostNetwork or
+// hostPID.
+func lintHostNamespaces(obj interface{}, sev wfv1.LintSeverity) []LintFinding {
+	var findings []LintFinding
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if hostNetwork, _ := m["hostNetwork"].(bool); hostNetwork {
+		findings = append(findings, LintFinding{Check: "privilegedOrHostNamespace", Severity: string(sev), Message: "pod spec sets hostNetwork: true"})
+	}
+	if hostPID, _ := m["hostPID"].(bool); hostPID {
+		findings = append(findings, LintFinding{Check: "privilegedOrHostNamespace", Severity: string(sev), Message: "pod spec sets hostPID: true"})
+	}
+	for _, val := range m {
+		switch child := val.(type) {
+		case map[string]interface{}:
+			findings = append(findings, lintHostNamespaces(child, sev)...)
+		case []interface{}:
+			for _, item := range child {
+				findings = append(findings, lintHostNamespaces(item, sev)...)
+			}
+		}
+	}
+	return findings
+}
+
+// lintNamespaceMismatch flags a manifest whose metadata.namespace is set and doesn't
+// match the executor's own namespace.
+func lintNamespaceMismatch(objJSON []byte, executorNamespace string, policy *wfv1.LintPolicy) *LintFinding {
+	if !lintRun(policy.NamespaceMismatch) {
+		return nil
+	}
+	manifestNamespace := gjson.GetBytes(objJSON, "metadata.namespace").String()
+	if manifestNamespace == "" || manifestNamespace == executorNamespace {
+		return nil
+	}
+	return &LintFinding{
+		Check:    "namespaceMismatch",
+		Severity: string(policy.NamespaceMismatch),
+		Message:  fmt.Sprintf("manifest namespace %q does not match the executor's namespace %q", manifestNamespace, executorNamespace),
+	}
+}
+
+// lintDeprecatedAPIVersion uses the discovery client to check whether the target server
+// still serves the manifest's Kind, either because the whole apiVersion was removed or
+// because that one Kind was dropped from a GroupVersion that still serves others (e.g.
+// PodSecurityPolicy removed from policy/v1beta1 while PodDisruptionBudget remained).
+func (we *WorkflowExecutor) lintDeprecatedAPIVersion(ctx context.Context, objJSON []byte, policy *wfv1.LintPolicy, kubeConfig *materializedKubeConfig) *LintFinding {
+	if !lintRun(policy.DeprecatedAPIVersion) {
+		return nil
+	}
+	apiVersion := gjson.GetBytes(objJSON, "apiVersion").String()
+	kind := gjson.GetBytes(objJSON, "kind").String()
+	if apiVersion == "" || kind == "" {
+		return nil
+	}
+
+	clientset, err := we.resourceClientSet(kubeConfig)
+	if err != nil {
+		log.Warnf("lint deprecatedApiVersion: could not build clientset to check discovery: %v", err)
+		return nil
+	}
+
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(apiVersion)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &LintFinding{
+				Check:    "deprecatedApiVersion",
+				Severity: string(policy.DeprecatedAPIVersion),
+				Message:  fmt.Sprintf("%s is not served by the target cluster; %s may have been removed", apiVersion, kind),
+			}
+		}
+		return nil
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Kind == kind {
+			return nil
+		}
+	}
+	return &LintFinding{
+		Check:    "deprecatedApiVersion",
+		Severity: string(policy.DeprecatedAPIVersion),
+		Message:  fmt.Sprintf("%s/%s is not served by the target cluster; it may have been removed", apiVersion, kind),
+	}
+}
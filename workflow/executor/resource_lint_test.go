@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	wfv1 "github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/argoproj/argo-workflows/v3/workflow/executor/mocks"
+)
+
+func TestLintResourceLimits(t *testing.T) {
+	c := map[string]interface{}{
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "100m"},
+		},
+	}
+	f := lintResourceLimits("main", c, wfv1.LintSeverityWarn)
+	assert.NotNil(t, f)
+	assert.Equal(t, "missingResourceLimits", f.Check)
+
+	c["resources"].(map[string]interface{})["limits"] = map[string]interface{}{"cpu": "200m"}
+	assert.Nil(t, lintResourceLimits("main", c, wfv1.LintSeverityWarn))
+}
+
+func TestLintImageTag(t *testing.T) {
+	assert.NotNil(t, lintImageTag("main", map[string]interface{}{"image": "nginx"}, wfv1.LintSeverityWarn))
+	assert.NotNil(t, lintImageTag("main", map[string]interface{}{"image": "nginx:latest"}, wfv1.LintSeverityWarn))
+	assert.Nil(t, lintImageTag("main", map[string]interface{}{"image": "nginx:1.21"}, wfv1.LintSeverityWarn))
+}
+
+func TestLintPrivileged(t *testing.T) {
+	assert.Nil(t, lintPrivileged("main", map[string]interface{}{}, wfv1.LintSeverityWarn))
+	c := map[string]interface{}{"securityContext": map[string]interface{}{"privileged": true}}
+	f := lintPrivileged("main", c, wfv1.LintSeverityError)
+	assert.NotNil(t, f)
+	assert.Equal(t, "privilegedOrHostNamespace", f.Check)
+}
+
+func TestLintProbes(t *testing.T) {
+	f := lintProbes("main", map[string]interface{}{}, wfv1.LintSeverityWarn)
+	assert.NotNil(t, f)
+	assert.Contains(t, f.Message, "readinessProbe")
+	assert.Contains(t, f.Message, "livenessProbe")
+
+	c := map[string]interface{}{
+		"readinessProbe": map[string]interface{}{},
+		"livenessProbe":  map[string]interface{}{},
+	}
+	assert.Nil(t, lintProbes("main", c, wfv1.LintSeverityWarn))
+}
+
+func TestLintHostNamespaces(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hostNetwork": true,
+			"hostPID":     true,
+		},
+	}
+	findings := lintHostNamespaces(obj, wfv1.LintSeverityWarn)
+	assert.Len(t, findings, 2)
+}
+
+func TestLintNamespaceMismatch(t *testing.T) {
+	policy := &wfv1.LintPolicy{NamespaceMismatch: wfv1.LintSeverityWarn}
+	objJSON := []byte(`{"metadata":{"namespace":"other"}}`)
+	f := lintNamespaceMismatch(objJSON, "argo", policy)
+	assert.NotNil(t, f)
+
+	objJSON = []byte(`{"metadata":{"namespace":"argo"}}`)
+	assert.Nil(t, lintNamespaceMismatch(objJSON, "argo", policy))
+}
+
+func TestFindContainers(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers":     []interface{}{map[string]interface{}{"name": "main"}},
+					"initContainers": []interface{}{map[string]interface{}{"name": "init"}},
+				},
+			},
+		},
+	}
+	containers := findContainers(obj)
+	assert.Len(t, containers, 2)
+}
+
+// TestLintDeprecatedAPIVersionGroupVersionRemoved covers the whole-GroupVersion-removed
+// case, where the discovery call itself 404s.
+func TestLintDeprecatedAPIVersionGroupVersionRemoved(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	we := &WorkflowExecutor{ClientSet: fakeClientset, Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	policy := &wfv1.LintPolicy{DeprecatedAPIVersion: wfv1.LintSeverityWarn}
+
+	objJSON := []byte(`{"apiVersion":"policy/v1beta1","kind":"PodSecurityPolicy"}`)
+	f := we.lintDeprecatedAPIVersion(context.Background(), objJSON, policy, nil)
+	assert.NotNil(t, f)
+	assert.Equal(t, "deprecatedApiVersion", f.Check)
+}
+
+// TestLintDeprecatedAPIVersionKindRemovedFromServedGroupVersion covers the case the
+// review flagged: a GroupVersion that's still served, but no longer serves this
+// particular Kind (e.g. PodSecurityPolicy removed from policy/v1beta1 while
+// PodDisruptionBudget remains).
+func TestLintDeprecatedAPIVersionKindRemovedFromServedGroupVersion(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "policy/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Namespaced: true},
+			},
+		},
+	}
+	we := &WorkflowExecutor{ClientSet: fakeClientset, Namespace: fakeNamespace, RuntimeExecutor: &mocks.ContainerRuntimeExecutor{}}
+	policy := &wfv1.LintPolicy{DeprecatedAPIVersion: wfv1.LintSeverityWarn}
+
+	objJSON := []byte(`{"apiVersion":"policy/v1beta1","kind":"PodSecurityPolicy"}`)
+	f := we.lintDeprecatedAPIVersion(context.Background(), objJSON, policy, nil)
+	assert.NotNil(t, f)
+	assert.Equal(t, "deprecatedApiVersion", f.Check)
+
+	objJSON = []byte(`{"apiVersion":"policy/v1beta1","kind":"PodDisruptionBudget"}`)
+	assert.Nil(t, we.lintDeprecatedAPIVersion(context.Background(), objJSON, policy, nil))
+}
@@ -0,0 +1,245 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultWaitForDeadline = 5 * time.Minute
+
+type waitForKind int
+
+const (
+	waitForCondition waitForKind = iota
+	waitForDelete
+	waitForJSONPath
+)
+
+// waitForSpec is a parsed WaitFor predicate.
+type waitForSpec struct {
+	Kind      waitForKind
+	Condition string
+	JSONPath  string
+	Value     string
+}
+
+// parseWaitFor parses a kubectl-wait-style predicate, e.g. "condition=Available",
+// "delete" or "jsonpath={.status.readyReplicas}=3".
+func parseWaitFor(s string) (*waitForSpec, error) {
+	switch {
+	case s == "":
+		return nil, nil
+	case s == "delete":
+		return &waitForSpec{Kind: waitForDelete}, nil
+	case strings.HasPrefix(s, "condition="):
+		return &waitForSpec{Kind: waitForCondition, Condition: strings.TrimPrefix(s, "condition=")}, nil
+	case strings.HasPrefix(s, "jsonpath="):
+		rest := strings.TrimPrefix(s, "jsonpath=")
+		idx := strings.Index(rest, "=")
+		if idx < 0 {
+			return nil, fmt.Errorf("waitFor jsonpath predicate must be of the form jsonpath={.path}=value, got %q", s)
+		}
+		path := strings.TrimSuffix(strings.TrimPrefix(rest[:idx], "{"), "}")
+		path = strings.TrimPrefix(path, ".")
+		return &waitForSpec{Kind: waitForJSONPath, JSONPath: path, Value: rest[idx+1:]}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized waitFor predicate %q", s)
+	}
+}
+
+// waitForResource polls the manifest's target object with exponential backoff until the
+// template's WaitFor predicate is satisfied, honoring ActiveDeadlineSeconds.
+func (we *WorkflowExecutor) waitForResource(ctx context.Context, manifestPath string, kubeConfig *materializedKubeConfig) error {
+	spec, err := parseWaitFor(we.Template.Resource.WaitFor)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+
+	kind, err := manifestKind(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(we.activeDeadline())
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 30 * time.Second
+
+	for {
+		getArgs := append([]string{"get", "-f", manifestPath, "-o", "json"}, kubeConfig.kubectlFlags()...)
+		objJSON, getErr := exec.CommandContext(ctx, "kubectl", getArgs...).Output()
+		exists := getErr == nil
+
+		ready, err := isResourceReady(kind, exists, objJSON, spec)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for waitFor=%q on %s", we.Template.Resource.WaitFor, kind)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// activeDeadline returns the template's ActiveDeadlineSeconds as a Duration, or a
+// sensible default if it wasn't set.
+func (we *WorkflowExecutor) activeDeadline() time.Duration {
+	if we.Template.ActiveDeadlineSeconds <= 0 {
+		return defaultWaitForDeadline
+	}
+	return time.Duration(we.Template.ActiveDeadlineSeconds) * time.Second
+}
+
+// manifestKind reads the `kind` field out of a manifest without otherwise decoding it.
+func manifestKind(manifestPath string) (string, error) {
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return "", err
+	}
+	var obj struct {
+		Kind string `json:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return "", err
+	}
+	return obj.Kind, nil
+}
+
+// isResourceReady evaluates a waitFor predicate against the live object, using
+// kind-aware readiness semantics for well-known built-in kinds when the predicate is a
+// generic "condition=" check.
+func isResourceReady(kind string, exists bool, objJSON []byte, spec *waitForSpec) (bool, error) {
+	switch spec.Kind {
+	case waitForDelete:
+		return !exists, nil
+	case waitForJSONPath:
+		if !exists {
+			return false, nil
+		}
+		res := gjson.GetBytes(objJSON, spec.JSONPath)
+		return res.Exists() && res.String() == spec.Value, nil
+	case waitForCondition:
+		if !exists {
+			return false, nil
+		}
+		if ready, ok, err := kindAwareReady(kind, objJSON, spec.Condition); ok {
+			return ready, err
+		}
+		return genericConditionTrue(objJSON, spec.Condition), nil
+	default:
+		return false, fmt.Errorf("unhandled waitFor predicate kind")
+	}
+}
+
+// kindAwareReady implements stronger readiness semantics for the handful of built-in
+// kinds where a bare status.conditions entry can lag behind, or be insufficient to
+// determine real readiness on its own. ok is false when the kind/condition pair isn't
+// one we special-case, in which case the caller falls back to a generic condition check.
+// err is non-nil when the object has reached a terminal state that the requested
+// condition can never be satisfied from (e.g. a Job that has Failed rather than
+// Completed), so the caller should stop polling and fail instead of reporting ready.
+func kindAwareReady(kind string, objJSON []byte, condition string) (ready bool, ok bool, err error) {
+	switch kind {
+	case "Deployment":
+		if condition != "Available" {
+			return false, false, nil
+		}
+		generation := gjson.GetBytes(objJSON, "metadata.generation").Int()
+		observedGeneration := gjson.GetBytes(objJSON, "status.observedGeneration").Int()
+		specReplicas := gjson.GetBytes(objJSON, "spec.replicas").Int()
+		updatedReplicas := gjson.GetBytes(objJSON, "status.updatedReplicas").Int()
+		availableReplicas := gjson.GetBytes(objJSON, "status.availableReplicas").Int()
+		return observedGeneration >= generation && updatedReplicas >= specReplicas && availableReplicas >= specReplicas, true, nil
+
+	case "StatefulSet":
+		if condition != "Available" {
+			return false, false, nil
+		}
+		generation := gjson.GetBytes(objJSON, "metadata.generation").Int()
+		observedGeneration := gjson.GetBytes(objJSON, "status.observedGeneration").Int()
+		specReplicas := gjson.GetBytes(objJSON, "spec.replicas").Int()
+		readyReplicas := gjson.GetBytes(objJSON, "status.readyReplicas").Int()
+		return observedGeneration >= generation && readyReplicas >= specReplicas, true, nil
+
+	case "DaemonSet":
+		if condition != "Available" {
+			return false, false, nil
+		}
+		desired := gjson.GetBytes(objJSON, "status.desiredNumberScheduled").Int()
+		ready := gjson.GetBytes(objJSON, "status.numberReady").Int()
+		return desired > 0 && ready >= desired, true, nil
+
+	case "Job":
+		if condition != "Complete" {
+			return false, false, nil
+		}
+		if genericConditionTrue(objJSON, "Failed") {
+			return false, true, fmt.Errorf("job failed")
+		}
+		return genericConditionTrue(objJSON, "Complete"), true, nil
+
+	case "Pod":
+		if condition != "Ready" {
+			return false, false, nil
+		}
+		containers := gjson.GetBytes(objJSON, "status.containerStatuses")
+		if !containers.Exists() {
+			return false, true, nil
+		}
+		allReady := true
+		containers.ForEach(func(_, c gjson.Result) bool {
+			if !c.Get("ready").Bool() {
+				allReady = false
+				return false
+			}
+			return true
+		})
+		return allReady, true, nil
+
+	case "PersistentVolumeClaim":
+		if condition != "Bound" {
+			return false, false, nil
+		}
+		return gjson.GetBytes(objJSON, "status.phase").String() == "Bound", true, nil
+
+	default:
+		return false, false, nil
+	}
+}
+
+// genericConditionTrue checks status.conditions[] for a condition of the given type
+// whose status is "True". This is the fallback for CRDs that follow the standard
+// status.conditions convention but aren't otherwise kind-aware.
+func genericConditionTrue(objJSON []byte, condType string) bool {
+	found := false
+	gjson.GetBytes(objJSON, "status.conditions").ForEach(func(_, c gjson.Result) bool {
+		if c.Get("type").String() == condType && c.Get("status").String() == "True" {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWaitFor(t *testing.T) {
+	spec, err := parseWaitFor("")
+	assert.NoError(t, err)
+	assert.Nil(t, spec)
+
+	spec, err = parseWaitFor("delete")
+	assert.NoError(t, err)
+	assert.Equal(t, &waitForSpec{Kind: waitForDelete}, spec)
+
+	spec, err = parseWaitFor("condition=Available")
+	assert.NoError(t, err)
+	assert.Equal(t, &waitForSpec{Kind: waitForCondition, Condition: "Available"}, spec)
+
+	spec, err = parseWaitFor("jsonpath={.status.readyReplicas}=3")
+	assert.NoError(t, err)
+	assert.Equal(t, &waitForSpec{Kind: waitForJSONPath, JSONPath: "status.readyReplicas", Value: "3"}, spec)
+
+	_, err = parseWaitFor("jsonpath={.status.readyReplicas}")
+	assert.Error(t, err)
+
+	_, err = parseWaitFor("bogus")
+	assert.Error(t, err)
+}
+
+func TestIsResourceReadyDelete(t *testing.T) {
+	spec, _ := parseWaitFor("delete")
+	ready, err := isResourceReady("ConfigMap", true, nil, spec)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	ready, err = isResourceReady("ConfigMap", false, nil, spec)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+}
+
+func TestIsResourceReadyJSONPath(t *testing.T) {
+	spec, _ := parseWaitFor("jsonpath={.status.readyReplicas}=3")
+	objJSON := []byte(`{"status":{"readyReplicas":3}}`)
+	ready, err := isResourceReady("Deployment", true, objJSON, spec)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+
+	objJSON = []byte(`{"status":{"readyReplicas":2}}`)
+	ready, err = isResourceReady("Deployment", true, objJSON, spec)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+}
+
+// TestKindAwareReadyJobComplete asserts that a Job with only a Complete condition is
+// reported ready for waitFor=condition=Complete.
+func TestKindAwareReadyJobComplete(t *testing.T) {
+	objJSON := []byte(`{"status":{"conditions":[{"type":"Complete","status":"True"}]}}`)
+	ready, ok, err := kindAwareReady("Job", objJSON, "Complete")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+}
+
+// TestKindAwareReadyJobFailed asserts that a failed Job never reports ready=true for
+// waitFor=condition=Complete, and instead returns an error so the poll stops.
+func TestKindAwareReadyJobFailed(t *testing.T) {
+	objJSON := []byte(`{"status":{"conditions":[{"type":"Failed","status":"True"}]}}`)
+	ready, ok, err := kindAwareReady("Job", objJSON, "Complete")
+	assert.True(t, ok)
+	assert.Error(t, err)
+	assert.False(t, ready)
+}
+
+func TestKindAwareReadyJobPending(t *testing.T) {
+	objJSON := []byte(`{"status":{"conditions":[]}}`)
+	ready, ok, err := kindAwareReady("Job", objJSON, "Complete")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+}
+
+func TestKindAwareReadyDeployment(t *testing.T) {
+	objJSON := []byte(`{
+		"metadata": {"generation": 2},
+		"spec": {"replicas": 3},
+		"status": {"observedGeneration": 2, "updatedReplicas": 3, "availableReplicas": 3}
+	}`)
+	ready, ok, err := kindAwareReady("Deployment", objJSON, "Available")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+}
+
+func TestKindAwareReadyUnhandledKind(t *testing.T) {
+	_, ok, err := kindAwareReady("ConfigMap", []byte(`{}`), "Available")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestGenericConditionTrue(t *testing.T) {
+	objJSON := []byte(`{"status":{"conditions":[{"type":"Ready","status":"True"}]}}`)
+	assert.True(t, genericConditionTrue(objJSON, "Ready"))
+	assert.False(t, genericConditionTrue(objJSON, "NotReady"))
+}